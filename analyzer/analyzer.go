@@ -0,0 +1,99 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package analyzer provides a golang.org/x/tools/go/analysis.Analyzer
+// that reports functions whose cyclomatic (or cognitive) complexity
+// exceeds a threshold, so gocyclo can be driven by go/analysis based
+// tools such as golangci-lint or a custom multichecker instead of
+// being shelled out to and having its text output parsed.
+package analyzer
+
+import (
+	"go/ast"
+	"reflect"
+	"regexp"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+
+	"github.com/fzipp/gocyclo"
+)
+
+const doc = `check cyclomatic complexities of functions
+
+The gocyclo analyzer reports functions whose complexity exceeds
+-over. By default it reports McCabe's cyclomatic complexity; with
+-cognitive it reports cognitive complexity instead. Its Result is an
+analyzer.Result holding the gocyclo.Stats of every analyzed function,
+for use by other analyzers.`
+
+// Analyzer reports functions with a cyclomatic (or, with -cognitive,
+// cognitive) complexity greater than -over. Its Result is a Result.
+var Analyzer = &analysis.Analyzer{
+	Name:       "gocyclo",
+	Doc:        doc,
+	Run:        run,
+	Requires:   []*analysis.Analyzer{inspect.Analyzer},
+	ResultType: reflect.TypeOf(Result{}),
+}
+
+// Result is the result type of Analyzer: the complexity statistics of
+// every function seen during the analysis run.
+type Result struct {
+	Stats gocyclo.Stats
+}
+
+var (
+	over      int
+	ignoreRx  string
+	cognitive bool
+)
+
+func init() {
+	Analyzer.Flags.IntVar(&over, "over", 10, "report functions with complexity > N only")
+	Analyzer.Flags.StringVar(&ignoreRx, "ignore", "", "exclude files matching the given regular expression")
+	Analyzer.Flags.BoolVar(&cognitive, "cognitive", false, "report cognitive complexity instead of cyclomatic complexity")
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	var ignore *regexp.Regexp
+	if ignoreRx != "" {
+		re, err := regexp.Compile(ignoreRx)
+		if err != nil {
+			return nil, err
+		}
+		ignore = re
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	nodeFilter := []ast.Node{(*ast.FuncDecl)(nil)}
+	var stats gocyclo.Stats
+	insp.Preorder(nodeFilter, func(n ast.Node) {
+		fn := n.(*ast.FuncDecl)
+		pos := pass.Fset.Position(fn.Pos())
+		if ignore != nil && ignore.MatchString(pos.Filename) {
+			return
+		}
+		stat := gocyclo.Stat{
+			PkgName:    pass.Pkg.Name(),
+			FuncName:   gocyclo.FuncName(fn),
+			Complexity: gocyclo.Complexity(fn),
+			Cognitive:  gocyclo.CognitiveComplexity(fn),
+			Pos:        pos,
+		}
+		stats = append(stats, stat)
+
+		metric, value := "cyclomatic", stat.Complexity
+		if cognitive {
+			metric, value = "cognitive", stat.Cognitive
+		}
+		if over > 0 && value > over {
+			pass.Reportf(fn.Pos(), "function %s has %s complexity %d (> %d)",
+				stat.FuncName, metric, value, over)
+		}
+	})
+
+	return Result{Stats: stats}, nil
+}
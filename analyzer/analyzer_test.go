@@ -0,0 +1,28 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analyzer_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/fzipp/gocyclo/analyzer"
+)
+
+func TestAnalyzer(t *testing.T) {
+	testdata := analysistest.TestData()
+	results := analysistest.Run(t, testdata, analyzer.Analyzer, "a")
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	result, ok := results[0].Result.(analyzer.Result)
+	if !ok {
+		t.Fatalf("result has type %T, want analyzer.Result", results[0].Result)
+	}
+	if len(result.Stats) != 2 {
+		t.Errorf("got %d stats, want 2 (simple and complex)", len(result.Stats))
+	}
+}
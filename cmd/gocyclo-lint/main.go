@@ -0,0 +1,21 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Gocyclo-lint runs the gocyclo analyzer as a standalone
+// go/analysis based vet-style checker.
+//
+// Usage:
+//
+//	gocyclo-lint [-over N] [-cognitive] [-ignore REGEX] <package> ...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/fzipp/gocyclo/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -17,9 +17,28 @@
 //	-avg, -avg-short      show the average complexity;
 //	                      the short option prints the value without a label
 //	-ignore REGEX         exclude files matching the given regular expression
+//	-cognitive             also compute cognitive complexity, and apply
+//	                      -over/-top/-avg to it instead of cyclomatic
+//	                      complexity
+//	-json                 print results as a JSON array instead of text
+//	-f TEMPLATE            print each result by executing the given
+//	                      text/template instead of the default text
+//	                      format, e.g. "{{.Complexity}} {{.PkgName}}"
+//	-report EDGE,...       print a complexity distribution table over
+//	                      the given bucket edges, e.g. -report=1,5,10,20
+//	-percentiles P,...     print the complexity at each given percentile,
+//	                      e.g. -percentiles=50,90,95,99
+//	-by-package            print total, mean, max and function count
+//	                      per package
+//	-by-file               print total, mean, max and function count
+//	                      per file
+//	-parallel N            number of files to parse concurrently
+//	                      (0 = runtime.GOMAXPROCS)
 //
 // The output fields for each line are:
 // <complexity> <package> <function> <file:line:column>
+// or, with -cognitive:
+// <complexity> <cognitive complexity> <package> <function> <file:line:column>
 package main
 
 import (
@@ -28,6 +47,7 @@ import (
 	"log"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -45,9 +65,28 @@ Flags:
     -avg, -avg-short      show the average complexity over all functions;
                           the short option prints the value without a label
     -ignore REGEX         exclude files matching the given regular expression
+    -cognitive             also compute cognitive complexity, and apply
+                          -over/-top/-avg to it instead of cyclomatic
+                          complexity
+    -json                 print results as a JSON array instead of text
+    -f TEMPLATE            print each result by executing the given
+                          text/template instead of the default text
+                          format, e.g. "{{.Complexity}} {{.PkgName}}"
+    -report EDGE,...       print a complexity distribution table over
+                          the given bucket edges, e.g. -report=1,5,10,20
+    -percentiles P,...     print the complexity at each given percentile,
+                          e.g. -percentiles=50,90,95,99
+    -by-package            print total, mean, max and function count
+                          per package
+    -by-file               print total, mean, max and function count
+                          per file
+    -parallel N            number of files to parse concurrently
+                          (0 = runtime.GOMAXPROCS)
 
 The output fields for each line are:
 <complexity> <package> <function> <file:line:column>
+or, with -cognitive:
+<complexity> <cognitive complexity> <package> <function> <file:line:column>
 `
 
 func main() {
@@ -57,7 +96,14 @@ func main() {
 	avg := flag.Bool("avg", false, "show the average complexity")
 	avgShort := flag.Bool("avg-short", false, "show the average complexity without a label")
 	ignore := flag.String("ignore", "", "exclude files matching the given regular expression")
-	report := flag.String("report", "", "show the general report")
+	cognitive := flag.Bool("cognitive", false, "also compute cognitive complexity; -over/-top/-avg apply to it instead")
+	jsonOut := flag.Bool("json", false, "print results as a JSON array")
+	tmpl := flag.String("f", "", "print each result by executing the given text/template")
+	report := flag.String("report", "", "print a complexity distribution table over the given bucket edges")
+	percentiles := flag.String("percentiles", "", "print the complexity at each given percentile")
+	byPackage := flag.Bool("by-package", false, "print total, mean, max and function count per package")
+	byFile := flag.Bool("by-file", false, "print total, mean, max and function count per file")
+	parallel := flag.Int("parallel", 0, "number of files to parse concurrently (0 = GOMAXPROCS)")
 
 	log.SetFlags(0)
 	log.SetPrefix("gocyclo: ")
@@ -68,29 +114,45 @@ func main() {
 		usage()
 	}
 
-	var breakPoints []int
-	if *report != "" {
-		stringBreakPoints := strings.Split(*report, ",")
-		breakPoints = make([]int, len(stringBreakPoints))
-		for _, breakPoint := range stringBreakPoints {
-			point, err := strconv.Atoi(breakPoint)
-			if err != nil {
-				usage()
-			}
-			breakPoints = append(breakPoints, point)
-		}
+	edges, err := parseInts(*report)
+	if err != nil {
+		usage()
+	}
+	percentilePoints, err := parseFloats(*percentiles)
+	if err != nil {
+		usage()
+	}
+
+	metric := gocyclo.Metric(gocyclo.Cyclomatic)
+	if *cognitive {
+		metric = gocyclo.CognitiveMetric
 	}
 
-	allStats := gocyclo.Analyze(paths, regex(*ignore))
-	shownStats := allStats.SortAndFilter(*top, *over, *under)
+	allStats := gocyclo.AnalyzeParallel(paths, regex(*ignore), *parallel)
+	shownStats := allStats.SortAndFilterBy(metric, *top, *over, *under)
 
-	printStats(shownStats)
-	if *avg || *avgShort {
-		printAverage(allStats, *avgShort)
+	formatter, err := formatterFor(*jsonOut, *tmpl, *cognitive, *avg || *avgShort, metric, allStats)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := formatter.Format(os.Stdout, shownStats); err != nil {
+		log.Fatal(err)
+	}
+	if (*avg || *avgShort) && !*jsonOut {
+		printAverage(allStats, metric, *avgShort)
 	}
 
-	if *report != "" {
-		printReport(shownStats, breakPoints)
+	if len(edges) > 0 {
+		printHistogram(allStats.HistogramBy(metric, edges))
+	}
+	if len(percentilePoints) > 0 {
+		printPercentiles(percentilePoints, allStats.PercentilesBy(metric, percentilePoints))
+	}
+	if *byPackage {
+		printGroups("PACKAGE", metric, allStats.GroupByPackage())
+	}
+	if *byFile {
+		printGroups("FILE", metric, allStats.GroupByFile())
 	}
 
 	if *over > 0 && len(shownStats) > 0 {
@@ -98,6 +160,42 @@ func main() {
 	}
 }
 
+// parseInts parses a comma-separated list of integers, e.g. "1,5,10".
+// An empty string yields a nil slice.
+func parseInts(s string) ([]int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	fields := strings.Split(s, ",")
+	values := make([]int, len(fields))
+	for i, field := range fields {
+		v, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// parseFloats parses a comma-separated list of floats, e.g.
+// "50,90,95,99". An empty string yields a nil slice.
+func parseFloats(s string) ([]float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	fields := strings.Split(s, ",")
+	values := make([]float64, len(fields))
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
 func regex(expr string) *regexp.Regexp {
 	if expr == "" {
 		return nil
@@ -109,25 +207,50 @@ func regex(expr string) *regexp.Regexp {
 	return re
 }
 
-func printStats(s gocyclo.Stats) {
-	for _, stat := range s {
-		fmt.Println(stat)
+// formatterFor picks the gocyclo.Formatter indicated by the -json and
+// -f flags, defaulting to gocyclo.TextFormatter.
+func formatterFor(jsonOut bool, tmpl string, cognitive, avg bool, metric gocyclo.Metric, allStats gocyclo.Stats) (gocyclo.Formatter, error) {
+	switch {
+	case jsonOut:
+		return gocyclo.JSONFormatter{Cognitive: cognitive, Average: avg, Metric: metric, All: allStats}, nil
+	case tmpl != "":
+		return gocyclo.NewTemplateFormatter(tmpl)
+	default:
+		return gocyclo.TextFormatter{Cognitive: cognitive}, nil
 	}
 }
 
-func printAverage(s gocyclo.Stats, short bool) {
+func printAverage(s gocyclo.Stats, metric gocyclo.Metric, short bool) {
 	if !short {
 		fmt.Print("Average: ")
 	}
-	fmt.Printf("%.3g\n", s.AverageComplexity())
+	fmt.Printf("%.3g\n", s.AverageBy(metric))
+}
+
+func printHistogram(buckets []gocyclo.Bucket) {
+	fmt.Println("RANGE\t|COUNT\t|PERCENT\t|CUMULATIVE%")
+	for _, b := range buckets {
+		fmt.Println(b)
+	}
 }
 
-func printReport(s gocyclo.Stats, breakPoints []int) {
-	totalComplexity := int(s.TotalComplexity())
-	last := -1
-	fmt.Println("RANGE\t|COUNT\t|PERCENT")
-	for key, value := range s.Report(breakPoints) {
-		fmt.Printf("[%d, %d) - %d - %d", key, last, value, value/totalComplexity*100)
+func printPercentiles(ps, values []float64) {
+	for i, p := range ps {
+		fmt.Printf("p%g: %.3g\n", p, values[i])
+	}
+}
+
+func printGroups(label string, metric gocyclo.Metric, groups map[string]gocyclo.Stats) {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Printf("%s\t|TOTAL\t|MEAN\t|MAX\t|COUNT\n", label)
+	for _, name := range names {
+		s := groups[name]
+		fmt.Printf("%s\t|%d\t|%.3g\t|%d\t|%d\n",
+			name, s.TotalBy(metric), s.AverageBy(metric), s.MaxBy(metric), len(s))
 	}
 }
 
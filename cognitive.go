@@ -0,0 +1,227 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocyclo
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// CognitiveComplexity calculates the cognitive complexity of a
+// function, following the rules of the "Cognitive Complexity" metric
+// introduced by SonarSource:
+//
+//   - control-flow structures (if, else/else if, switch, a case inside
+//     a type switch, for/range, select, goto, and a break/continue
+//     that targets a label) each add a flat +1
+//   - if, switch, for/range, select and function literals also
+//     increase the nesting depth for anything nested inside them,
+//     adding that depth on top of the flat increment of the nested
+//     structure (an else does not increase the nesting depth itself)
+//   - each run of like binary logical operators (&&/||) adds +1; a
+//     change of operator inside the same expression starts a new run
+//   - a recursive call to the enclosing function adds +1
+func CognitiveComplexity(fn *ast.FuncDecl) int {
+	if fn.Body == nil {
+		// A function without a body, e.g. one implemented in
+		// assembly, has no control flow to measure.
+		return 0
+	}
+	v := &cognitiveVisitor{funcName: fn.Name.Name, isMethod: fn.Recv != nil}
+	v.stmt(fn.Body, 0)
+	return v.complexity
+}
+
+type cognitiveVisitor struct {
+	funcName   string
+	isMethod   bool
+	complexity int
+}
+
+func (v *cognitiveVisitor) stmt(s ast.Stmt, nesting int) {
+	if s == nil {
+		return
+	}
+	switch s := s.(type) {
+	case *ast.BlockStmt:
+		for _, stmt := range s.List {
+			v.stmt(stmt, nesting)
+		}
+	case *ast.IfStmt:
+		v.ifStmt(s, nesting)
+	case *ast.ForStmt:
+		v.stmt(s.Init, nesting)
+		v.stmt(s.Post, nesting)
+		v.expr(s.Cond, nesting)
+		v.complexity += 1 + nesting
+		v.stmt(s.Body, nesting+1)
+	case *ast.RangeStmt:
+		v.expr(s.X, nesting)
+		v.complexity += 1 + nesting
+		v.stmt(s.Body, nesting+1)
+	case *ast.SwitchStmt:
+		v.stmt(s.Init, nesting)
+		v.expr(s.Tag, nesting)
+		v.complexity += 1 + nesting
+		for _, clause := range s.Body.List {
+			cc := clause.(*ast.CaseClause)
+			for _, e := range cc.List {
+				v.expr(e, nesting)
+			}
+			for _, stmt := range cc.Body {
+				v.stmt(stmt, nesting+1)
+			}
+		}
+	case *ast.TypeSwitchStmt:
+		v.stmt(s.Init, nesting)
+		v.complexity += 1 + nesting
+		for _, clause := range s.Body.List {
+			cc := clause.(*ast.CaseClause)
+			// Unlike a plain switch, every case of a type switch is
+			// its own control-flow break.
+			v.complexity++
+			for _, stmt := range cc.Body {
+				v.stmt(stmt, nesting+1)
+			}
+		}
+	case *ast.SelectStmt:
+		v.complexity += 1 + nesting
+		for _, clause := range s.Body.List {
+			cc := clause.(*ast.CommClause)
+			for _, stmt := range cc.Body {
+				v.stmt(stmt, nesting+1)
+			}
+		}
+	case *ast.BranchStmt:
+		if s.Tok == token.GOTO || s.Label != nil {
+			v.complexity++
+		}
+	case *ast.LabeledStmt:
+		v.stmt(s.Stmt, nesting)
+	case *ast.ExprStmt:
+		v.expr(s.X, nesting)
+	case *ast.AssignStmt:
+		for _, rhs := range s.Rhs {
+			v.expr(rhs, nesting)
+		}
+	case *ast.SendStmt:
+		v.expr(s.Chan, nesting)
+		v.expr(s.Value, nesting)
+	case *ast.ReturnStmt:
+		for _, r := range s.Results {
+			v.expr(r, nesting)
+		}
+	case *ast.GoStmt:
+		v.expr(s.Call, nesting)
+	case *ast.DeferStmt:
+		v.expr(s.Call, nesting)
+	}
+}
+
+// ifStmt handles an if/else-if/else chain as a single unit: every
+// link in the chain adds a flat +1 plus the current nesting depth,
+// but only the condition's own body increases the nesting depth for
+// anything nested inside it; the chain itself does not nest deeper
+// with each else-if.
+func (v *cognitiveVisitor) ifStmt(s *ast.IfStmt, nesting int) {
+	v.stmt(s.Init, nesting)
+	v.complexity += 1 + nesting
+	v.expr(s.Cond, nesting)
+	v.stmt(s.Body, nesting+1)
+	switch els := s.Else.(type) {
+	case *ast.IfStmt:
+		v.ifStmt(els, nesting)
+	case *ast.BlockStmt:
+		v.complexity++
+		v.stmt(els, nesting+1)
+	}
+}
+
+func (v *cognitiveVisitor) expr(e ast.Expr, nesting int) {
+	if e == nil {
+		return
+	}
+	switch e := e.(type) {
+	case *ast.BinaryExpr:
+		if isLogicalOp(e.Op) {
+			v.logicalOpRun(e, nesting)
+			return
+		}
+		v.expr(e.X, nesting)
+		v.expr(e.Y, nesting)
+	case *ast.CallExpr:
+		if v.isRecursiveCall(e.Fun) {
+			v.complexity++
+		}
+		v.expr(e.Fun, nesting)
+		for _, a := range e.Args {
+			v.expr(a, nesting)
+		}
+	case *ast.FuncLit:
+		v.stmt(e.Body, nesting+1)
+	case *ast.UnaryExpr:
+		v.expr(e.X, nesting)
+	case *ast.ParenExpr:
+		v.expr(e.X, nesting)
+	case *ast.StarExpr:
+		v.expr(e.X, nesting)
+	case *ast.SelectorExpr:
+		v.expr(e.X, nesting)
+	case *ast.IndexExpr:
+		v.expr(e.X, nesting)
+		v.expr(e.Index, nesting)
+	case *ast.TypeAssertExpr:
+		v.expr(e.X, nesting)
+	case *ast.KeyValueExpr:
+		v.expr(e.Value, nesting)
+	}
+}
+
+// logicalOpRun walks a chain of &&/|| binary expressions left to
+// right and adds +1 for each run of like operators; a change of
+// operator starts a new run and adds another +1.
+func (v *cognitiveVisitor) logicalOpRun(top *ast.BinaryExpr, nesting int) {
+	var ops []token.Token
+	var collect func(e ast.Expr)
+	collect = func(e ast.Expr) {
+		be, ok := e.(*ast.BinaryExpr)
+		if !ok || !isLogicalOp(be.Op) {
+			v.expr(e, nesting)
+			return
+		}
+		collect(be.X)
+		ops = append(ops, be.Op)
+		collect(be.Y)
+	}
+	collect(top)
+	prevOp := token.ILLEGAL
+	for _, op := range ops {
+		if op != prevOp {
+			v.complexity++
+		}
+		prevOp = op
+	}
+}
+
+// isRecursiveCall reports whether fun is a call to the enclosing
+// function or method. For a method, this is a best effort check since
+// it has no type information: it treats any selector call whose
+// selector name matches the enclosing method's name as recursive,
+// without verifying that the receiver is of the same type.
+func (v *cognitiveVisitor) isRecursiveCall(fun ast.Expr) bool {
+	switch f := fun.(type) {
+	case *ast.Ident:
+		// Inside a method, a plain identifier call cannot refer to
+		// the method itself.
+		return !v.isMethod && f.Name == v.funcName
+	case *ast.SelectorExpr:
+		return v.isMethod && f.Sel.Name == v.funcName
+	}
+	return false
+}
+
+func isLogicalOp(op token.Token) bool {
+	return op == token.LAND || op == token.LOR
+}
@@ -0,0 +1,105 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocyclo
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+// parseFunc parses src as the body of a Go source file and returns
+// the *ast.FuncDecl named name.
+func parseFunc(t *testing.T, src, name string) *ast.FuncDecl {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "test.go", "package p\n"+src, 0)
+	if err != nil {
+		t.Fatalf("parse error: %v\n%s", err, src)
+	}
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == name {
+			return fn
+		}
+	}
+	t.Fatalf("no function %q found in:\n%s", name, src)
+	return nil
+}
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"empty", `func f() {}`, 1},
+		{"if", `func f(a bool) { if a { } }`, 2},
+		{"if-else", `func f(a bool) { if a { } else { } }`, 2},
+		{"for", `func f() { for i := 0; i < 10; i++ { } }`, 2},
+		{"range", `func f(s []int) { for range s { } }`, 2},
+		{"logical-and", `func f(a, b bool) bool { return a && b }`, 2},
+		{"logical-and-or", `func f(a, b, c bool) bool { return a && b || c }`, 3},
+		{"switch-cases", `func f(a int) { switch a { case 1: case 2: } }`, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := parseFunc(t, tt.src, "f")
+			if got := Complexity(fn); got != tt.want {
+				t.Errorf("Complexity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCognitiveComplexity(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"empty", `func f() {}`, 0},
+		{"bodyless", `func f()`, 0},
+		{"if", `func f(a bool) { if a { } }`, 1},
+		{"if-else", `func f(a bool) { if a { } else { } }`, 2},
+		{"if-else-if", `func f(a, b bool) { if a { } else if b { } }`, 2},
+		{"nested-if", `func f(a, b bool) { if a { if b { } } }`, 3}, // 1 + (1+1)
+		{"and-chain", `func f(a, b, c bool) bool { return a && b && c }`, 1},
+		{"and-or-chain", `func f(a, b, c bool) bool { return a && b || c }`, 2},
+		{"switch-cases", `func f(a int) { switch a { case 1: case 2: } }`, 1},
+		{"type-switch-cases", `func f(a any) { switch a.(type) { case int: case string: } }`, 3}, // 1 + 1 + 1
+		{"goto", `func f() { goto L; L: }`, 1},
+		{"labeled-break", `func f() { L: for { break L } }`, 2}, // for(1) + break L(1)
+		{"plain-break", `func f() { for { break } }`, 1},        // for(1), unlabeled break doesn't count
+		{
+			"recursive-func",
+			`func f(n int) int { if n == 0 { return 0 }; return f(n - 1) }`,
+			2, // if(1) + recursive call(1)
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn := parseFunc(t, tt.src, "f")
+			if got := CognitiveComplexity(fn); got != tt.want {
+				t.Errorf("CognitiveComplexity() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCognitiveComplexityRecursiveMethod(t *testing.T) {
+	src := `
+type T struct{}
+func (t T) Rec(n int) int {
+	if n == 0 {
+		return 0
+	}
+	return t.Rec(n - 1)
+}`
+	fn := parseFunc(t, src, "Rec")
+	want := 2 // if(1) + recursive method call(1)
+	if got := CognitiveComplexity(fn); got != want {
+		t.Errorf("CognitiveComplexity() = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocyclo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/template"
+)
+
+// Formatter writes a Stats list to w in some output format.
+type Formatter interface {
+	Format(w io.Writer, stats Stats) error
+}
+
+// TextFormatter writes stats in gocyclo's traditional plain text
+// format, one function per line.
+type TextFormatter struct {
+	// Cognitive, if true, also prints the cognitive complexity of
+	// each function as a second column.
+	Cognitive bool
+}
+
+func (f TextFormatter) Format(w io.Writer, stats Stats) error {
+	for _, stat := range stats {
+		var err error
+		if f.Cognitive {
+			_, err = fmt.Fprintf(w, "%d %d %s %s %s\n",
+				stat.Complexity, stat.Cognitive, stat.PkgName, stat.FuncName, stat.Pos)
+		} else {
+			_, err = fmt.Fprintln(w, stat)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonStat is the JSON representation of a single Stat entry, or, if
+// Complexity/Average is the zero value pair, a trailing summary
+// entry.
+type jsonStat struct {
+	Complexity int     `json:"complexity,omitempty"`
+	Cognitive  int     `json:"cognitive,omitempty"`
+	Package    string  `json:"package,omitempty"`
+	Function   string  `json:"function,omitempty"`
+	File       string  `json:"file,omitempty"`
+	Line       int     `json:"line,omitempty"`
+	Column     int     `json:"column,omitempty"`
+	Average    float64 `json:"average,omitempty"`
+	Total      int64   `json:"total,omitempty"`
+	Count      int     `json:"count,omitempty"`
+}
+
+// JSONFormatter writes stats as a JSON array of objects with fields
+// complexity, package, function, file, line and column. If Cognitive
+// is true, each object also gets a cognitive field, mirroring
+// TextFormatter.Cognitive. If Average is true, a trailing object with
+// average, total and count fields is appended to the array, computed
+// by Metric (Cyclomatic if nil) over All (the stats passed to Format
+// if All is nil) rather than over the entries being printed, so it
+// matches the summary the text formatter prints alongside -avg
+// regardless of -over/-top/-under filtering. Fields whose value is
+// zero are omitted from every object, so a function with a cognitive
+// complexity of 0 has no cognitive field, and a summary average or
+// total of 0 is left out of the summary object.
+type JSONFormatter struct {
+	Cognitive bool
+	Average   bool
+	Metric    Metric
+	All       Stats
+}
+
+func (f JSONFormatter) Format(w io.Writer, stats Stats) error {
+	entries := make([]jsonStat, 0, len(stats)+1)
+	for _, stat := range stats {
+		entry := jsonStat{
+			Complexity: stat.Complexity,
+			Package:    stat.PkgName,
+			Function:   stat.FuncName,
+			File:       stat.Pos.Filename,
+			Line:       stat.Pos.Line,
+			Column:     stat.Pos.Column,
+		}
+		if f.Cognitive {
+			entry.Cognitive = stat.Cognitive
+		}
+		entries = append(entries, entry)
+	}
+	if f.Average {
+		metric := f.Metric
+		if metric == nil {
+			metric = Cyclomatic
+		}
+		all := f.All
+		if all == nil {
+			all = stats
+		}
+		entries = append(entries, jsonStat{
+			Average: all.AverageBy(metric),
+			Total:   all.TotalBy(metric),
+			Count:   len(all),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// TemplateFormatter writes stats by executing a text/template once
+// per Stat, with the Stat's exported fields (Complexity, PkgName,
+// FuncName, Pos, Cognitive) available to the template, e.g.
+// "{{.Complexity}} {{.PkgName}}.{{.FuncName}} {{.Pos}}".
+type TemplateFormatter struct {
+	tmpl *template.Template
+}
+
+// NewTemplateFormatter parses text as a text/template and returns a
+// TemplateFormatter that executes it once per Stat.
+func NewTemplateFormatter(text string) (*TemplateFormatter, error) {
+	tmpl, err := template.New("gocyclo").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateFormatter{tmpl: tmpl}, nil
+}
+
+func (f *TemplateFormatter) Format(w io.Writer, stats Stats) error {
+	for _, stat := range stats {
+		if err := f.tmpl.Execute(w, stat); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocyclo
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func testStats() Stats {
+	return Stats{
+		{PkgName: "p", FuncName: "A", Complexity: 1, Cognitive: 0, Pos: token.Position{Filename: "a.go", Line: 1, Column: 1}},
+		{PkgName: "p", FuncName: "B", Complexity: 5, Cognitive: 7, Pos: token.Position{Filename: "b.go", Line: 2, Column: 1}},
+	}
+}
+
+func TestTextFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	stats := testStats()
+	if err := (TextFormatter{}).Format(&buf, stats); err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{"1 p A a.go:1:1", "5 p B b.go:2:1"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+}
+
+func TestTextFormatterCognitive(t *testing.T) {
+	var buf bytes.Buffer
+	stats := testStats()
+	if err := (TextFormatter{Cognitive: true}).Format(&buf, stats); err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	got := buf.String()
+	if !strings.Contains(got, "5 7 p B b.go:2:1") {
+		t.Errorf("output %q does not contain cognitive column", got)
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	stats := testStats()
+	if err := (JSONFormatter{}).Format(&buf, stats); err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	var entries []jsonStat
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[1].Function != "B" || entries[1].Complexity != 5 {
+		t.Errorf("entries[1] = %+v, want Function=B Complexity=5", entries[1])
+	}
+}
+
+// TestJSONFormatterSummaryUsesAll verifies that the summary object
+// reflects All (the full, unfiltered population) and Metric, not the
+// (possibly filtered) stats passed to Format.
+func TestJSONFormatterSummaryUsesAll(t *testing.T) {
+	all := testStats()
+	shown := all[1:] // simulate -over having filtered out function A
+
+	var buf bytes.Buffer
+	f := JSONFormatter{Average: true, Metric: Cyclomatic, All: all}
+	if err := f.Format(&buf, shown); err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	var entries []jsonStat
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	summary := entries[len(entries)-1]
+	wantAvg := all.AverageComplexity()
+	if summary.Average != wantAvg {
+		t.Errorf("summary average = %v, want %v (over all, not shown)", summary.Average, wantAvg)
+	}
+	if summary.Count != len(all) {
+		t.Errorf("summary count = %d, want %d (over all, not shown)", summary.Count, len(all))
+	}
+
+	var bufCognitive bytes.Buffer
+	fCognitive := JSONFormatter{Average: true, Metric: CognitiveMetric, All: all}
+	if err := fCognitive.Format(&bufCognitive, shown); err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	var cogEntries []jsonStat
+	if err := json.Unmarshal(bufCognitive.Bytes(), &cogEntries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, bufCognitive.String())
+	}
+	cogSummary := cogEntries[len(cogEntries)-1]
+	wantCogAvg := all.AverageBy(CognitiveMetric)
+	if cogSummary.Average != wantCogAvg {
+		t.Errorf("cognitive summary average = %v, want %v", cogSummary.Average, wantCogAvg)
+	}
+}
+
+// TestJSONFormatterCognitive verifies that -cognitive's per-row
+// "cognitive" field is populated, and that "complexity" still reports
+// the cyclomatic value rather than being replaced by it.
+func TestJSONFormatterCognitive(t *testing.T) {
+	var buf bytes.Buffer
+	stats := testStats()
+	if err := (JSONFormatter{Cognitive: true}).Format(&buf, stats); err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	var entries []jsonStat
+	if err := json.Unmarshal(buf.Bytes(), &entries); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if entries[1].Complexity != 5 || entries[1].Cognitive != 7 {
+		t.Errorf("entries[1] = %+v, want Complexity=5 Cognitive=7", entries[1])
+	}
+}
+
+func TestTemplateFormatter(t *testing.T) {
+	f, err := NewTemplateFormatter("{{.Complexity}} {{.PkgName}}.{{.FuncName}}")
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := f.Format(&buf, testStats()); err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	want := "1 p.A\n5 p.B\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
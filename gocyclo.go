@@ -0,0 +1,240 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package gocyclo calculates cyclomatic complexities of functions in
+// Go source code.
+//
+// The cyclomatic complexity of a function is calculated according to
+// the following rules:
+//
+//	1 is the base complexity of a function
+//	+1 for each 'if', 'for', 'case' (including 'default'), '&&' or '||'
+package gocyclo
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"log"
+	"sort"
+	"sync"
+)
+
+// Stat holds the complexity numbers and position of a single function.
+type Stat struct {
+	PkgName    string
+	FuncName   string
+	Complexity int
+	// Cognitive is the cognitive complexity of the function, see
+	// CognitiveComplexity.
+	Cognitive int
+	Pos       token.Position
+}
+
+func (s Stat) String() string {
+	return fmt.Sprintf("%d %s %s %s", s.Complexity, s.PkgName, s.FuncName, s.Pos)
+}
+
+// Metric selects which complexity number of a Stat to sort, filter or
+// average by.
+type Metric func(Stat) int
+
+// Cyclomatic is the Metric for Stat.Complexity, the McCabe cyclomatic
+// complexity.
+func Cyclomatic(s Stat) int { return s.Complexity }
+
+// CognitiveMetric is the Metric for Stat.Cognitive, the cognitive
+// complexity.
+func CognitiveMetric(s Stat) int { return s.Cognitive }
+
+// Stats is a list of Stat.
+type Stats []Stat
+
+func (s Stats) Len() int { return len(s) }
+func (s Stats) Less(i, j int) bool {
+	if s[i].Complexity != s[j].Complexity {
+		return s[i].Complexity >= s[j].Complexity
+	}
+	// Break ties on file:line:column so that the result is
+	// deterministic regardless of the order in which stats were
+	// gathered, e.g. by the concurrent walk in AnalyzeParallel.
+	pi, pj := s[i].Pos, s[j].Pos
+	if pi.Filename != pj.Filename {
+		return pi.Filename < pj.Filename
+	}
+	if pi.Line != pj.Line {
+		return pi.Line < pj.Line
+	}
+	return pi.Column < pj.Column
+}
+func (s Stats) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+
+// SortAndFilter sorts the stats by descending cyclomatic complexity
+// and returns at most top entries (all of them if top is negative)
+// whose complexity is greater than over and less than under (when
+// those bounds are positive).
+func (s Stats) SortAndFilter(top, over, under int) Stats {
+	return s.SortAndFilterBy(Cyclomatic, top, over, under)
+}
+
+// SortAndFilterBy is like SortAndFilter but sorts and filters by the
+// given Metric instead of always using cyclomatic complexity.
+func (s Stats) SortAndFilterBy(metric Metric, top, over, under int) Stats {
+	sorted := make(Stats, len(s))
+	copy(sorted, s)
+	sort.Sort(byMetric{sorted, metric})
+	var result Stats
+	for i, stat := range sorted {
+		if top >= 0 && i >= top {
+			break
+		}
+		v := metric(stat)
+		if over > 0 && v <= over {
+			continue
+		}
+		if under > 0 && v >= under {
+			continue
+		}
+		result = append(result, stat)
+	}
+	return result
+}
+
+type byMetric struct {
+	Stats
+	metric Metric
+}
+
+func (b byMetric) Less(i, j int) bool {
+	vi, vj := b.metric(b.Stats[i]), b.metric(b.Stats[j])
+	if vi != vj {
+		return vi >= vj
+	}
+	// Break ties on file:line:column so that the result is
+	// deterministic regardless of the order in which stats were
+	// gathered, e.g. by the concurrent walk in AnalyzeParallel.
+	pi, pj := b.Stats[i].Pos, b.Stats[j].Pos
+	if pi.Filename != pj.Filename {
+		return pi.Filename < pj.Filename
+	}
+	if pi.Line != pj.Line {
+		return pi.Line < pj.Line
+	}
+	return pi.Column < pj.Column
+}
+
+// AverageComplexity returns the average cyclomatic complexity over
+// all functions in s.
+func (s Stats) AverageComplexity() float64 {
+	return s.AverageBy(Cyclomatic)
+}
+
+// AverageBy returns the average of the given Metric over all
+// functions in s.
+func (s Stats) AverageBy(metric Metric) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	return float64(s.TotalBy(metric)) / float64(len(s))
+}
+
+// TotalComplexity returns the sum of the cyclomatic complexities of
+// all functions in s.
+func (s Stats) TotalComplexity() int64 {
+	return s.TotalBy(Cyclomatic)
+}
+
+// TotalBy returns the sum of the given Metric over all functions in
+// s.
+func (s Stats) TotalBy(metric Metric) int64 {
+	var total int64
+	for _, stat := range s {
+		total += int64(metric(stat))
+	}
+	return total
+}
+
+func analyzeFile(fsetMu *sync.Mutex, fset *token.FileSet, path string) Stats {
+	f, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+	var stats Stats
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		fsetMu.Lock()
+		pos := fset.Position(fn.Pos())
+		fsetMu.Unlock()
+		stats = append(stats, Stat{
+			PkgName:    f.Name.Name,
+			FuncName:   FuncName(fn),
+			Complexity: Complexity(fn),
+			Cognitive:  CognitiveComplexity(fn),
+			Pos:        pos,
+		})
+	}
+	return stats
+}
+
+// FuncName returns the name of a function, including the receiver
+// type for methods, e.g. "(*Foo).Bar" for a method Bar on *Foo.
+func FuncName(fn *ast.FuncDecl) string {
+	if fn.Recv != nil {
+		if fn.Recv.NumFields() > 0 {
+			typ := fn.Recv.List[0].Type
+			return fmt.Sprintf("(%s).%s", recvString(typ), fn.Name)
+		}
+	}
+	return fn.Name.Name
+}
+
+func recvString(typ ast.Expr) string {
+	switch t := typ.(type) {
+	case *ast.StarExpr:
+		return "*" + recvString(t.X)
+	case *ast.Ident:
+		return t.Name
+	}
+	return fmt.Sprintf("%v", typ)
+}
+
+// Complexity calculates the cyclomatic complexity of a function.
+func Complexity(fn *ast.FuncDecl) int {
+	v := complexityVisitor{complexity: 1}
+	ast.Walk(&v, fn)
+	return v.complexity
+}
+
+type complexityVisitor struct {
+	complexity int
+}
+
+func (v *complexityVisitor) Visit(n ast.Node) ast.Visitor {
+	switch n := n.(type) {
+	case *ast.FuncDecl, *ast.FuncLit:
+		// The base complexity of 1 is already accounted for by the
+		// visitor itself, nested function literals are walked and
+		// contribute their own control-flow nodes below.
+	case *ast.IfStmt:
+		v.complexity++
+	case *ast.ForStmt:
+		v.complexity++
+	case *ast.RangeStmt:
+		v.complexity++
+	case *ast.CaseClause:
+		v.complexity++
+	case *ast.CommClause:
+		v.complexity++
+	case *ast.BinaryExpr:
+		if n.Op == token.LAND || n.Op == token.LOR {
+			v.complexity++
+		}
+	}
+	return v
+}
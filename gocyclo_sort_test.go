@@ -0,0 +1,45 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocyclo
+
+import (
+	"go/token"
+	"testing"
+)
+
+// TestSortAndFilterByStableOnTies verifies that functions tied on
+// complexity are ordered deterministically by file:line:column,
+// regardless of their input order. This matters because
+// AnalyzeParallel gathers results from concurrent workers, so the
+// input order is no longer tied to a deterministic sequential walk.
+func TestSortAndFilterByStableOnTies(t *testing.T) {
+	b := Stat{PkgName: "p", FuncName: "B", Complexity: 3, Pos: token.Position{Filename: "b.go", Line: 1, Column: 1}}
+	a := Stat{PkgName: "p", FuncName: "A", Complexity: 3, Pos: token.Position{Filename: "a.go", Line: 5, Column: 1}}
+	c := Stat{PkgName: "p", FuncName: "C", Complexity: 3, Pos: token.Position{Filename: "a.go", Line: 1, Column: 1}}
+
+	for _, input := range []Stats{{b, a, c}, {c, b, a}, {a, c, b}} {
+		got := input.SortAndFilterBy(Cyclomatic, -1, 0, 0)
+		if len(got) != 3 || got[0].FuncName != "C" || got[1].FuncName != "A" || got[2].FuncName != "B" {
+			t.Errorf("SortAndFilterBy(%v) = %v, want order C, A, B", names(input), names(got))
+		}
+	}
+}
+
+func names(s Stats) []string {
+	ns := make([]string, len(s))
+	for i, stat := range s {
+		ns[i] = stat.FuncName
+	}
+	return ns
+}
+
+func TestStatsSortStableOnTies(t *testing.T) {
+	b := Stat{FuncName: "B", Complexity: 2, Pos: token.Position{Filename: "b.go", Line: 1}}
+	a := Stat{FuncName: "A", Complexity: 2, Pos: token.Position{Filename: "a.go", Line: 1}}
+	s := Stats{b, a}
+	if !s.Less(1, 0) || s.Less(0, 1) {
+		t.Errorf("Stats.Less did not break the tie by filename: Less(1,0)=%v Less(0,1)=%v", s.Less(1, 0), s.Less(0, 1))
+	}
+}
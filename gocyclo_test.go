@@ -0,0 +1,40 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocyclo
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// goRootSrc returns the standard library source tree to use as a
+// large, realistic input for the benchmarks below, or "" if it isn't
+// available in the current environment.
+func goRootSrc(b *testing.B) string {
+	b.Helper()
+	src := filepath.Join(runtime.GOROOT(), "src")
+	if info, err := os.Stat(src); err != nil || !info.IsDir() {
+		b.Skipf("standard library source not found at %s", src)
+	}
+	return src
+}
+
+func BenchmarkAnalyzeParallel(b *testing.B) {
+	src := goRootSrc(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AnalyzeParallel([]string{src}, nil, 0)
+	}
+}
+
+func BenchmarkAnalyzeSequential(b *testing.B) {
+	src := goRootSrc(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		AnalyzeParallel([]string{src}, nil, 1)
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocyclo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// Bucket is one row of a complexity distribution as computed by
+// Stats.Histogram: the half-open range [Low, High) (Low == math.MinInt
+// means unbounded below, the underflow bucket; High == -1 means
+// unbounded above, the last bucket), the number of functions whose
+// complexity falls into that range, its percentage of the total, and
+// the cumulative percentage up to and including this bucket.
+type Bucket struct {
+	Low        int
+	High       int
+	Count      int
+	Percent    float64
+	Cumulative float64
+}
+
+func (b Bucket) String() string {
+	low := strconv.Itoa(b.Low)
+	if b.Low == math.MinInt {
+		low = "-Inf"
+	}
+	high := "+Inf"
+	if b.High != -1 {
+		high = strconv.Itoa(b.High)
+	}
+	return fmt.Sprintf("[%s, %s)\t%d\t%.1f%%\t%.1f%%", low, high, b.Count, b.Percent, b.Cumulative)
+}
+
+// Histogram buckets the cyclomatic complexities in s by the given
+// edges. It is like HistogramBy but always uses Cyclomatic.
+func (s Stats) Histogram(edges []int) []Bucket {
+	return s.HistogramBy(Cyclomatic, edges)
+}
+
+// HistogramBy buckets the given Metric over s by the given edges and
+// returns len(edges)+1 buckets: an underflow bucket (-Inf, edges[0])
+// for values below the lowest edge, a half-open range
+// [edges[i], edges[i+1]) for every adjacent pair of edges, and an
+// unbounded range [edges[last], +Inf) for values at or above the
+// highest edge. Every Stat in s falls into exactly one bucket, so
+// percentages always add up to 100%. edges need not be sorted, and
+// must be non-empty.
+func (s Stats) HistogramBy(metric Metric, edges []int) []Bucket {
+	if len(edges) == 0 {
+		return nil
+	}
+	sorted := append([]int(nil), edges...)
+	sort.Ints(sorted)
+
+	buckets := make([]Bucket, len(sorted)+1)
+	buckets[0] = Bucket{Low: math.MinInt, High: sorted[0]}
+	for i, edge := range sorted {
+		buckets[i+1].Low = edge
+		buckets[i+1].High = -1
+		if i+1 < len(sorted) {
+			buckets[i+1].High = sorted[i+1]
+		}
+	}
+	for _, stat := range s {
+		v := metric(stat)
+		for i := range buckets {
+			if inBucket(v, buckets[i]) {
+				buckets[i].Count++
+				break
+			}
+		}
+	}
+	total := float64(len(s))
+	cumulative := 0.0
+	for i := range buckets {
+		if total > 0 {
+			buckets[i].Percent = float64(buckets[i].Count) / total * 100
+		}
+		cumulative += buckets[i].Percent
+		buckets[i].Cumulative = cumulative
+	}
+	return buckets
+}
+
+func inBucket(complexity int, b Bucket) bool {
+	if b.Low != math.MinInt && complexity < b.Low {
+		return false
+	}
+	return b.High == -1 || complexity < b.High
+}
+
+// Percentiles returns the cyclomatic complexity at each of the given
+// percentiles. It is like PercentilesBy but always uses Cyclomatic.
+func (s Stats) Percentiles(ps []float64) []float64 {
+	return s.PercentilesBy(Cyclomatic, ps)
+}
+
+// PercentilesBy returns the value of the given Metric at each of the
+// given percentiles (0-100), using linear interpolation between the
+// two nearest ranks. It returns a slice of zeros if s is empty.
+func (s Stats) PercentilesBy(metric Metric, ps []float64) []float64 {
+	result := make([]float64, len(ps))
+	if len(s) == 0 {
+		return result
+	}
+	values := make([]int, len(s))
+	for i, stat := range s {
+		values[i] = metric(stat)
+	}
+	sort.Ints(values)
+	for i, p := range ps {
+		result[i] = percentile(values, p)
+	}
+	return result
+}
+
+func percentile(sorted []int, p float64) float64 {
+	if len(sorted) == 1 {
+		return float64(sorted[0])
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return float64(sorted[lo])
+	}
+	frac := rank - float64(lo)
+	return float64(sorted[lo]) + frac*float64(sorted[hi]-sorted[lo])
+}
+
+// GroupByPackage splits s into one Stats per package name.
+func (s Stats) GroupByPackage() map[string]Stats {
+	groups := make(map[string]Stats)
+	for _, stat := range s {
+		groups[stat.PkgName] = append(groups[stat.PkgName], stat)
+	}
+	return groups
+}
+
+// GroupByFile splits s into one Stats per source file.
+func (s Stats) GroupByFile() map[string]Stats {
+	groups := make(map[string]Stats)
+	for _, stat := range s {
+		groups[stat.Pos.Filename] = append(groups[stat.Pos.Filename], stat)
+	}
+	return groups
+}
+
+// MaxComplexity returns the highest cyclomatic complexity in s, or 0
+// if s is empty. It is like MaxBy but always uses Cyclomatic.
+func (s Stats) MaxComplexity() int {
+	return s.MaxBy(Cyclomatic)
+}
+
+// MaxBy returns the highest value of the given Metric in s, or 0 if s
+// is empty.
+func (s Stats) MaxBy(metric Metric) int {
+	max := 0
+	for _, stat := range s {
+		if v := metric(stat); v > max {
+			max = v
+		}
+	}
+	return max
+}
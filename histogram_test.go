@@ -0,0 +1,197 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocyclo
+
+import (
+	"go/token"
+	"math"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	s := Stats{
+		{Complexity: 4},
+		{Complexity: 2},
+	}
+	buckets := s.Histogram([]int{5, 10, 20})
+	if len(buckets) != 4 {
+		t.Fatalf("got %d buckets, want 4 (underflow + 3 edges)", len(buckets))
+	}
+
+	underflow := buckets[0]
+	if underflow.Low != math.MinInt || underflow.High != 5 {
+		t.Errorf("underflow bucket = %+v, want Low=MinInt High=5", underflow)
+	}
+	if underflow.Count != 2 {
+		t.Errorf("underflow bucket count = %d, want 2 (both functions are below the lowest edge)", underflow.Count)
+	}
+
+	total := 0
+	cumulative := 0.0
+	for _, b := range buckets {
+		total += b.Count
+		cumulative = b.Cumulative
+	}
+	if total != len(s) {
+		t.Errorf("bucket counts sum to %d, want %d (no function should be silently dropped)", total, len(s))
+	}
+	if math.Abs(cumulative-100) > 1e-9 {
+		t.Errorf("final cumulative percentage = %v, want 100", cumulative)
+	}
+}
+
+// TestHistogramByCognitive verifies that HistogramBy buckets by the
+// given Metric rather than always using cyclomatic complexity, using
+// the reviewer's reproduction: a function with cyclomatic 4 /
+// cognitive 2 must land in the [1, 3) bucket under CognitiveMetric,
+// not the [3, +Inf) bucket its cyclomatic complexity would put it in.
+func TestHistogramByCognitive(t *testing.T) {
+	s := Stats{
+		{Complexity: 3, Cognitive: 3},
+		{Complexity: 4, Cognitive: 2},
+	}
+	buckets := s.HistogramBy(CognitiveMetric, []int{1, 3})
+	if buckets[0].Count != 0 {
+		t.Errorf("underflow bucket count = %d, want 0", buckets[0].Count)
+	}
+	if buckets[1].Count != 1 {
+		t.Errorf("[1, 3) bucket count = %d, want 1 (the cognitive-2 function)", buckets[1].Count)
+	}
+	if buckets[2].Count != 1 {
+		t.Errorf("[3, +Inf) bucket count = %d, want 1 (the cognitive-3 function)", buckets[2].Count)
+	}
+}
+
+func TestHistogramEmptyEdges(t *testing.T) {
+	s := Stats{{Complexity: 1}}
+	if buckets := s.Histogram(nil); buckets != nil {
+		t.Errorf("Histogram(nil) = %v, want nil", buckets)
+	}
+}
+
+func TestHistogramUnsortedEdges(t *testing.T) {
+	s := Stats{{Complexity: 7}}
+	buckets := s.Histogram([]int{10, 1, 5})
+	want := []int{1, 5, 10}
+	for i, edge := range want {
+		if buckets[i+1].Low != edge {
+			t.Errorf("buckets[%d].Low = %d, want %d", i+1, buckets[i+1].Low, edge)
+		}
+	}
+}
+
+func TestBucketString(t *testing.T) {
+	tests := []struct {
+		b    Bucket
+		want string
+	}{
+		{Bucket{Low: math.MinInt, High: 5, Count: 2, Percent: 50, Cumulative: 50}, "[-Inf, 5)\t2\t50.0%\t50.0%"},
+		{Bucket{Low: 10, High: -1, Count: 1, Percent: 25, Cumulative: 100}, "[10, +Inf)\t1\t25.0%\t100.0%"},
+	}
+	for _, tt := range tests {
+		if got := tt.b.String(); got != tt.want {
+			t.Errorf("Bucket.String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	s := Stats{
+		{Complexity: 1},
+		{Complexity: 2},
+		{Complexity: 3},
+		{Complexity: 4},
+		{Complexity: 5},
+	}
+	got := s.Percentiles([]float64{0, 50, 100})
+	want := []float64{1, 3, 5}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Percentiles()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPercentilesEmpty(t *testing.T) {
+	got := Stats{}.Percentiles([]float64{50, 90})
+	want := []float64{0, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Percentiles()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// TestPercentilesByCognitive verifies that PercentilesBy computes
+// percentiles over the given Metric rather than always using
+// cyclomatic complexity.
+func TestPercentilesByCognitive(t *testing.T) {
+	s := Stats{
+		{Complexity: 3, Cognitive: 3},
+		{Complexity: 4, Cognitive: 2},
+	}
+	got := s.PercentilesBy(CognitiveMetric, []float64{50})
+	want := 2.5
+	if got[0] != want {
+		t.Errorf("PercentilesBy(CognitiveMetric, [50])[0] = %v, want %v", got[0], want)
+	}
+}
+
+func TestGroupByPackage(t *testing.T) {
+	s := Stats{
+		{PkgName: "a", FuncName: "F"},
+		{PkgName: "b", FuncName: "G"},
+		{PkgName: "a", FuncName: "H"},
+	}
+	groups := s.GroupByPackage()
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	if len(groups["a"]) != 2 {
+		t.Errorf("group a has %d stats, want 2", len(groups["a"]))
+	}
+	if len(groups["b"]) != 1 {
+		t.Errorf("group b has %d stats, want 1", len(groups["b"]))
+	}
+}
+
+func TestGroupByFile(t *testing.T) {
+	s := Stats{
+		{Pos: token.Position{Filename: "a.go"}},
+		{Pos: token.Position{Filename: "b.go"}},
+		{Pos: token.Position{Filename: "a.go"}},
+	}
+	groups := s.GroupByFile()
+	if len(groups["a.go"]) != 2 {
+		t.Errorf("group a.go has %d stats, want 2", len(groups["a.go"]))
+	}
+	if len(groups["b.go"]) != 1 {
+		t.Errorf("group b.go has %d stats, want 1", len(groups["b.go"]))
+	}
+}
+
+func TestMaxComplexity(t *testing.T) {
+	s := Stats{
+		{Complexity: 3},
+		{Complexity: 9},
+		{Complexity: 1},
+	}
+	if got := s.MaxComplexity(); got != 9 {
+		t.Errorf("MaxComplexity() = %d, want 9", got)
+	}
+	if got := (Stats{}).MaxComplexity(); got != 0 {
+		t.Errorf("MaxComplexity() on empty Stats = %d, want 0", got)
+	}
+}
+
+func TestMaxBy(t *testing.T) {
+	s := Stats{
+		{Complexity: 9, Cognitive: 1},
+		{Complexity: 1, Cognitive: 9},
+	}
+	if got := s.MaxBy(CognitiveMetric); got != 9 {
+		t.Errorf("MaxBy(CognitiveMetric) = %d, want 9", got)
+	}
+}
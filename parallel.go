@@ -0,0 +1,95 @@
+// Copyright 2013 Frederik Zipp. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package gocyclo
+
+import (
+	"io/fs"
+	"log"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"go/token"
+)
+
+// Analyze calculates the cyclomatic complexities of the functions and
+// methods found in the Go source files in the given paths. Paths may
+// be files or directories, directories are searched recursively. Files
+// whose path matches the ignore regular expression, if it is non-nil,
+// are skipped.
+//
+// Analyze walks and parses files using a worker pool sized to
+// runtime.GOMAXPROCS. Use AnalyzeParallel to control the number of
+// workers.
+func Analyze(paths []string, ignore *regexp.Regexp) Stats {
+	return AnalyzeParallel(paths, ignore, 0)
+}
+
+// AnalyzeParallel is like Analyze but parses files concurrently using
+// parallel worker goroutines. If parallel is 0 or negative,
+// runtime.GOMAXPROCS(0) workers are used.
+func AnalyzeParallel(paths []string, ignore *regexp.Regexp, parallel int) Stats {
+	if parallel <= 0 {
+		parallel = runtime.GOMAXPROCS(0)
+	}
+
+	files := make(chan string)
+	go func() {
+		defer close(files)
+		for _, root := range paths {
+			walkGoFiles(root, ignore, files)
+		}
+	}()
+
+	fset := token.NewFileSet()
+	var fsetMu sync.Mutex
+
+	results := make(chan Stats)
+	var workers sync.WaitGroup
+	workers.Add(parallel)
+	for i := 0; i < parallel; i++ {
+		go func() {
+			defer workers.Done()
+			var stats Stats
+			for path := range files {
+				stats = append(stats, analyzeFile(&fsetMu, fset, path)...)
+			}
+			results <- stats
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	var stats Stats
+	for s := range results {
+		stats = append(stats, s...)
+	}
+	return stats
+}
+
+// walkGoFiles walks root, sending the path of every Go source file
+// not matching ignore to out.
+func walkGoFiles(root string, ignore *regexp.Regexp, out chan<- string) {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+		if ignore != nil && ignore.MatchString(path) {
+			return nil
+		}
+		out <- path
+		return nil
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}